@@ -2,128 +2,57 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/ollama/ollama/api"
-)
-
-// ConvertToOllamaTools converts MCP tools to Ollama format
-func ConvertToOllamaTools(tools []mcp.Tool) []api.Tool {
-	ollamaTools := make([]api.Tool, len(tools))
-	for i, tool := range tools {
-		ollamaTools[i] = api.Tool{
-			Type: "function",
-			Function: api.ToolFunction{
-				Name:        tool.Name,
-				Description: tool.Description,
-				Parameters: struct {
-					Type       string   `json:"type"`
-					Required   []string `json:"required"`
-					Properties map[string]struct {
-						Type        string   `json:"type"`
-						Description string   `json:"description"`
-						Enum        []string `json:"enum,omitempty"`
-					} `json:"properties"`
-				}{
-					Type:       tool.InputSchema.Type,
-					Required:   tool.InputSchema.Required,
-					Properties: convertProperties(tool.InputSchema.Properties),
-				},
-			},
-		}
-	}
-	return ollamaTools
-}
-
-// Helper function to convert properties to Ollama's format
-func convertProperties(props map[string]interface{}) map[string]struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description"`
-	Enum        []string `json:"enum,omitempty"`
-} {
-	result := make(map[string]struct {
-		Type        string   `json:"type"`
-		Description string   `json:"description"`
-		Enum        []string `json:"enum,omitempty"`
-	})
-
-	for name, prop := range props {
-		if propMap, ok := prop.(map[string]interface{}); ok {
-			prop := struct {
-				Type        string   `json:"type"`
-				Description string   `json:"description"`
-				Enum        []string `json:"enum,omitempty"`
-			}{
-				Type:        getString(propMap, "type"),
-				Description: getString(propMap, "description"),
-			}
-
-			// Handle enum if present
-			if enumRaw, ok := propMap["enum"].([]interface{}); ok {
-				for _, e := range enumRaw {
-					if str, ok := e.(string); ok {
-						prop.Enum = append(prop.Enum, str)
-					}
-				}
-			}
-
-			result[name] = prop
-		}
-	}
 
-	return result
-}
-
-// Helper function to safely get string values from map
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key].(string); ok {
-		return v
-	}
-	return ""
-}
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/agent"
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/mcptransport"
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/provider"
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/rag"
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/repl"
+)
 
 func main() {
-	ctx := context.Background()
-
-	// Get Ollama host from env or use default
-	var ollamaRawUrl string
-	if ollamaRawUrl = os.Getenv("OLLAMA_HOST"); ollamaRawUrl == "" {
-		ollamaRawUrl = "http://localhost:11434"
+	interactive := flag.Bool("i", false, "interactive mode: keep a conversation open across turns")
+	flag.Parse()
+
+	// Set up model name from env, falling back to the legacy TOOLS_LLM var
+	// used before the provider layer existed.
+	toolsLLM := os.Getenv("LLM_MODEL")
+	if toolsLLM == "" {
+		toolsLLM = os.Getenv("TOOLS_LLM")
 	}
-
-	// Set up model names from env
-	var toolsLLM string
-	if toolsLLM = os.Getenv("TOOLS_LLM"); toolsLLM == "" {
+	if toolsLLM == "" {
 		toolsLLM = "qwen2.5:0.5b-instruct-max"
 	}
 
-	// Create Ollama client
-	url, _ := url.Parse(ollamaRawUrl)
-	ollamaClient := api.NewClient(url, http.DefaultClient)
+	// Build the LLM provider selected by LLM_PROVIDER (defaults to Ollama, so
+	// the client keeps working the way it always has out of the box).
+	chatProvider, err := provider.New(provider.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("😡 Failed to build LLM provider: %v", err)
+	}
+
+	// Initialization and tool listing get a bounded timeout; the
+	// conversation itself (below) does not, since an interactive session can
+	// run indefinitely.
+	initCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	// Create MCP client - using bun to run your TypeScript server
-	mcpClient, err := client.NewStdioMCPClient(
-		"bun",
-		[]string{}, // Empty ENV
-		"run",
-		"start:mcp",
-	)
+	// Create the MCP client over whichever transport MCP_TRANSPORT selects
+	// (defaults to running the bundled TypeScript server over stdio).
+	mcpClient, err := mcptransport.NewClient(initCtx, mcptransport.ConfigFromEnv())
 	if err != nil {
 		log.Fatalf("😡 Failed to create client: %v", err)
 	}
 	defer mcpClient.Close()
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	// Initialize MCP client
 	fmt.Println("🚀 Initializing mcp client...")
 	initRequest := mcp.InitializeRequest{}
@@ -133,7 +62,7 @@ func main() {
 		Version: "1.0.0",
 	}
 
-	initResult, err := mcpClient.Initialize(ctx, initRequest)
+	initResult, err := mcpClient.Initialize(initCtx, initRequest)
 	if err != nil {
 		log.Fatalf("Failed to initialize: %v", err)
 	}
@@ -145,7 +74,7 @@ func main() {
 	// List Tools
 	fmt.Println("🛠️ Available tools...")
 	toolsRequest := mcp.ListToolsRequest{}
-	tools, err := mcpClient.ListTools(ctx, toolsRequest)
+	tools, err := mcpClient.ListTools(initCtx, toolsRequest)
 	if err != nil {
 		log.Fatalf("😡 Failed to list tools: %v", err)
 	}
@@ -157,88 +86,80 @@ func main() {
 	}
 	fmt.Println()
 
-	// Convert tools to Ollama format
-	ollamaTools := ConvertToOllamaTools(tools.Tools)
-
-	// Display the Ollama format
-	fmt.Println("🦙 Ollama tools:")
-	fmt.Println(ollamaTools)
-
-	// Setup chat with Ollama
-	messages := []api.Message{
-		{
-			Role:    "system",
-			Content: "You are a helpful assistant that analyzes web content. After receiving the extracted content, provide a clear and concise summary focusing on the main points. Don't just repeat the raw content.",
-		},
-		{
-			Role:    "user",
-			Content: "Extract and summarize the content from this URL: 'https://www.scrapethissite.com/pages/'. What are the key features and learning resources offered?",
-		},
+	// Build the agent: it owns the message history and the combined MCP +
+	// local tool registry, and drives the call-model/dispatch-tools loop
+	// until the model stops asking for tools.
+	a := agent.New(chatProvider, mcpClient, toolsLLM, map[string]interface{}{
+		"temperature":   0.2,
+		"num_predict":   2048, // Increased token limit
+		"repeat_last_n": 64,   // Better context handling
+	})
+	a.SetSystemPrompt("You are a helpful assistant that analyzes web content. After receiving the extracted content, provide a clear and concise summary focusing on the main points. Don't just repeat the raw content. You can chain multiple tool calls - for example, fetching a page, following a link it mentions, and summarizing both.")
+	a.RegisterMCPTools(tools.Tools)
+	a.RegisterLocalTool(agent.DirTreeTool())
+	a.RegisterLocalTool(agent.ReadFileTool())
+
+	// RAG: keep large extracted pages out of the prompt by chunking,
+	// embedding, and retrieving only the chunks relevant to the user's
+	// question before the model's summarization turn.
+	ragCachePath := os.Getenv("RAG_CACHE_PATH")
+	if ragCachePath == "" {
+		ragCachePath = "rag-cache.gob"
+	}
+	ragCollection, err := rag.NewCollection(ragCachePath)
+	if err != nil {
+		log.Fatalf("😡 Failed to load RAG cache: %v", err)
 	}
+	defer func() {
+		if err := ragCollection.Save(); err != nil {
+			log.Printf("⚠️  Failed to save RAG cache: %v", err)
+		}
+	}()
+	embed := rag.OllamaEmbedder(os.Getenv("OLLAMA_HOST"), os.Getenv("EMBED_MODEL"))
+
+	a.ToolResultFilter = func(ctx context.Context, userMessage string, call provider.ToolCall, result agent.CallResult) (agent.CallResult, error) {
+		if call.Name != "extract" {
+			return result, nil
+		}
+		url, _ := call.Arguments["url"].(string)
+		key := rag.Key(url, result.Content)
 
-	var FALSE = false
-	req := &api.ChatRequest{
-		Model:    toolsLLM,
-		Messages: messages,
-		Options: map[string]interface{}{
-			"temperature":   0.2,
-			"num_predict":   2048, // Increased token limit
-			"repeat_last_n": 64,   // Better context handling
-		},
-		Tools:  ollamaTools,
-		Stream: &FALSE,
+		if err := ragCollection.Ingest(ctx, key, result.Content, embed); err != nil {
+			return result, err
+		}
+		topChunks, err := ragCollection.Search(ctx, key, userMessage, rag.DefaultTopK, embed)
+		if err != nil || len(topChunks) == 0 {
+			return result, err
+		}
+		return agent.CallResult{Content: strings.Join(topChunks, "\n\n---\n\n")}, nil
 	}
 
-	err = ollamaClient.Chat(ctx, req, func(resp api.ChatResponse) error {
-		// Print initial model response if any
-		if resp.Message.Content != "" {
-			fmt.Printf("\n🦙 Model Response: %s\n", resp.Message.Content)
+	ctx := context.Background()
+
+	if *interactive || repl.IsTerminal(os.Stdin) {
+		if err := repl.New(a, os.Stdin, os.Stdout).Run(ctx); err != nil {
+			log.Fatalf("😡 Interactive session failed: %v", err)
 		}
+		return
+	}
 
-		for _, toolCall := range resp.Message.ToolCalls {
-			fmt.Printf("\n🛠️  Tool Call: %s\n", toolCall.Function.Name)
-			fmt.Printf("Arguments: %s\n", toolCall.Function.Arguments)
-
-			callRequest := mcp.CallToolRequest{
-				Request: mcp.Request{Method: "tools/call"},
-			}
-			callRequest.Params.Name = toolCall.Function.Name
-			callRequest.Params.Arguments = toolCall.Function.Arguments
-
-			result, err := mcpClient.CallTool(ctx, callRequest)
-			if err != nil {
-				log.Printf("❌ Tool call failed: %v\n", err)
-				return err
-			}
-
-			// Format the content for better readability
-			var contentText string
-			for _, content := range result.Content {
-				if contentMap, ok := content.(map[string]interface{}); ok {
-					if text, ok := contentMap["text"].(string); ok {
-						contentText += text
-					}
-				}
-			}
-
-			// Add tool results to chat context with better formatting
-			toolMessage := api.Message{
-				Role:    "tool",
-				Content: contentText,
-			}
-			req.Messages = append(req.Messages, toolMessage)
-
-			// Add a follow-up message requesting analysis
-			analysisRequest := api.Message{
-				Role:    "user",
-				Content: "Based on the extracted content above, please provide a concise summary of the main points and key features offered on this website.",
-			}
-			req.Messages = append(req.Messages, analysisRequest)
+	printedHeader := false
+	onChunk := func(chunk provider.Chunk) {
+		if chunk.ContentDelta == "" {
+			return
 		}
-		return nil
-	})
+		if !printedHeader {
+			fmt.Print("\n🦙 Model Response: ")
+			printedHeader = true
+		}
+		fmt.Print(chunk.ContentDelta)
+	}
 
+	_, err = a.Run(ctx, "Extract and summarize the content from this URL: 'https://www.scrapethissite.com/pages/'. What are the key features and learning resources offered?", onChunk)
+	if printedHeader {
+		fmt.Println()
+	}
 	if err != nil {
-		log.Fatalf("😡 Failed to chat with Ollama: %v", err)
+		log.Fatalf("😡 Agent run failed: %v", err)
 	}
 }
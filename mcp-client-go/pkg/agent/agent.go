@@ -0,0 +1,257 @@
+// Package agent drives the multi-turn tool-calling loop: it keeps calling
+// the model with the growing message history until it answers with no more
+// tool calls (or an iteration cap is hit), dispatching each tool call to
+// whichever backend owns it — an MCP server or a locally-implemented Go
+// function.
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/provider"
+)
+
+// DefaultMaxIterations caps how many times the agent will call the model
+// and dispatch tool calls for a single Run before giving up, in case the
+// model never stops asking for tools.
+const DefaultMaxIterations = 8
+
+// CallResult is what a tool returns, in the same shape regardless of
+// whether it came from an MCP server or a local Impl.
+type CallResult struct {
+	Content string
+}
+
+// ToolSpec describes a locally-implemented tool: its name, description and
+// JSON-schema parameters (so it can be advertised to the model exactly like
+// an MCP tool), plus the Go function that actually runs it.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Parameters  mcp.ToolInputSchema
+	Impl        func(ctx context.Context, args map[string]interface{}) (CallResult, error)
+}
+
+func (s ToolSpec) toMCPTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        s.Name,
+		Description: s.Description,
+		InputSchema: s.Parameters,
+	}
+}
+
+// ToolCaller is implemented by an MCP client; it's the subset of
+// mcp-go/client.Client the agent needs, kept narrow so tests can fake it.
+type ToolCaller interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// Agent owns the conversation history and the combined tool registry (MCP +
+// local), and runs the call-model/dispatch-tools loop on behalf of main.
+type Agent struct {
+	provider      provider.ChatProvider
+	mcpClient     ToolCaller
+	model         string
+	options       map[string]interface{}
+	messages      []provider.Message
+	localTools    map[string]ToolSpec
+	mcpTools      []mcp.Tool
+	maxIterations int
+
+	// ToolResultFilter, if set, post-processes a tool's raw result before it
+	// is fed back to the model - e.g. to replace a large extracted page with
+	// only the chunks relevant to the user's question (see pkg/rag).
+	ToolResultFilter func(ctx context.Context, userMessage string, call provider.ToolCall, result CallResult) (CallResult, error)
+
+	// OnToolCall, if set, is notified of each tool call before it is
+	// dispatched, so a caller (e.g. the REPL) can surface tool activity.
+	OnToolCall func(call provider.ToolCall)
+}
+
+// New builds an Agent around a chat provider and the MCP client used to
+// dispatch MCP-owned tool calls.
+func New(chatProvider provider.ChatProvider, mcpClient ToolCaller, model string, options map[string]interface{}) *Agent {
+	return &Agent{
+		provider:      chatProvider,
+		mcpClient:     mcpClient,
+		model:         model,
+		options:       options,
+		localTools:    make(map[string]ToolSpec),
+		maxIterations: DefaultMaxIterations,
+	}
+}
+
+// SetMaxIterations overrides DefaultMaxIterations.
+func (a *Agent) SetMaxIterations(n int) {
+	a.maxIterations = n
+}
+
+// SetSystemPrompt sets (or replaces) the leading system message.
+func (a *Agent) SetSystemPrompt(prompt string) {
+	if len(a.messages) > 0 && a.messages[0].Role == "system" {
+		a.messages[0].Content = prompt
+		return
+	}
+	a.messages = append([]provider.Message{{Role: "system", Content: prompt}}, a.messages...)
+}
+
+// SetModel switches the model used for subsequent Run calls, e.g. from the
+// REPL's /model command.
+func (a *Agent) SetModel(model string) {
+	a.model = model
+}
+
+// Model returns the model currently in use.
+func (a *Agent) Model() string {
+	return a.model
+}
+
+// Reset clears the conversation history, keeping the system prompt (if any)
+// so a fresh conversation still carries it.
+func (a *Agent) Reset() {
+	if len(a.messages) > 0 && a.messages[0].Role == "system" {
+		a.messages = a.messages[:1]
+		return
+	}
+	a.messages = nil
+}
+
+// History returns the full conversation so far, e.g. for the REPL's /save
+// command.
+func (a *Agent) History() []provider.Message {
+	return a.messages
+}
+
+// ToolNames returns the names of every registered tool, MCP and local.
+func (a *Agent) ToolNames() []string {
+	names := make([]string, 0, len(a.mcpTools)+len(a.localTools))
+	for _, t := range a.mcpTools {
+		names = append(names, t.Name)
+	}
+	for name := range a.localTools {
+		names = append(names, name)
+	}
+	return names
+}
+
+// RegisterMCPTools advertises the server's tools to the model.
+func (a *Agent) RegisterMCPTools(tools []mcp.Tool) {
+	a.mcpTools = append(a.mcpTools, tools...)
+}
+
+// RegisterLocalTool advertises a Go-implemented tool to the model.
+func (a *Agent) RegisterLocalTool(spec ToolSpec) {
+	a.localTools[spec.Name] = spec
+}
+
+// allTools returns the MCP and local tools merged into one list, in the
+// common mcp.Tool shape the provider layer already knows how to convert.
+func (a *Agent) allTools() []mcp.Tool {
+	all := make([]mcp.Tool, 0, len(a.mcpTools)+len(a.localTools))
+	all = append(all, a.mcpTools...)
+	for _, spec := range a.localTools {
+		all = append(all, spec.toMCPTool())
+	}
+	return all
+}
+
+// Run appends userMessage to the history and drives the agent loop: call the
+// model, dispatch any tool calls it asks for, feed the results back, repeat
+// until the model answers with no tool calls or maxIterations is reached.
+// It returns the model's final text answer.
+func (a *Agent) Run(ctx context.Context, userMessage string, onChunk func(provider.Chunk)) (string, error) {
+	a.messages = append(a.messages, provider.Message{Role: "user", Content: userMessage})
+
+	tools := a.provider.ConvertTools(a.allTools())
+
+	var final string
+	for i := 0; i < a.maxIterations; i++ {
+		req := provider.ChatRequest{
+			Model:    a.model,
+			Messages: a.messages,
+			Options:  a.options,
+			Tools:    tools,
+		}
+
+		chunks, err := a.provider.Chat(ctx, req)
+		if err != nil {
+			return "", err
+		}
+
+		var content string
+		var toolCalls []provider.ToolCall
+		for chunk := range chunks {
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+			if chunk.Err != nil {
+				return "", fmt.Errorf("agent: chat stream failed: %w", chunk.Err)
+			}
+			content += chunk.ContentDelta
+			if chunk.Done {
+				toolCalls = chunk.ToolCalls
+			}
+		}
+
+		if content != "" || len(toolCalls) > 0 {
+			a.messages = append(a.messages, provider.Message{Role: "assistant", Content: content, ToolCalls: toolCalls})
+		}
+		final = content
+
+		if len(toolCalls) == 0 {
+			return final, nil
+		}
+
+		for _, call := range toolCalls {
+			if a.OnToolCall != nil {
+				a.OnToolCall(call)
+			}
+			result, err := a.dispatch(ctx, call)
+			if err != nil {
+				result = CallResult{Content: fmt.Sprintf("tool %q failed: %v", call.Name, err)}
+			} else if a.ToolResultFilter != nil {
+				if filtered, err := a.ToolResultFilter(ctx, userMessage, call, result); err == nil {
+					result = filtered
+				}
+			}
+			a.messages = append(a.messages, provider.Message{
+				Role:       "tool",
+				Content:    result.Content,
+				ToolCallID: call.ID,
+				ToolName:   call.Name,
+			})
+		}
+	}
+
+	return final, fmt.Errorf("agent: hit max iterations (%d) without a final answer", a.maxIterations)
+}
+
+// dispatch routes a tool call to whichever backend owns it: a
+// locally-implemented Impl, or the MCP client.
+func (a *Agent) dispatch(ctx context.Context, call provider.ToolCall) (CallResult, error) {
+	if spec, ok := a.localTools[call.Name]; ok {
+		return spec.Impl(ctx, call.Arguments)
+	}
+
+	callRequest := mcp.CallToolRequest{
+		Request: mcp.Request{Method: "tools/call"},
+	}
+	callRequest.Params.Name = call.Name
+	callRequest.Params.Arguments = call.Arguments
+
+	result, err := a.mcpClient.CallTool(ctx, callRequest)
+	if err != nil {
+		return CallResult{}, err
+	}
+
+	var text string
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			text += tc.Text
+		}
+	}
+	return CallResult{Content: text}, nil
+}
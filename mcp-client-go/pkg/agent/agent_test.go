@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/provider"
+)
+
+// fakeProvider returns one scripted response (content + tool calls) per
+// call to Chat, in order, so a test can script a multi-turn conversation.
+type fakeProvider struct {
+	responses []provider.Chunk
+	calls     int
+	gotReqs   []provider.ChatRequest
+}
+
+func (f *fakeProvider) ConvertTools(tools []mcp.Tool) any { return tools }
+
+func (f *fakeProvider) Chat(ctx context.Context, req provider.ChatRequest) (<-chan provider.Chunk, error) {
+	f.gotReqs = append(f.gotReqs, req)
+	if f.calls >= len(f.responses) {
+		return nil, errors.New("fakeProvider: ran out of scripted responses")
+	}
+	resp := f.responses[f.calls]
+	f.calls++
+
+	out := make(chan provider.Chunk, 1)
+	out <- resp
+	close(out)
+	return out, nil
+}
+
+// fakeToolCaller never actually gets called in these tests since every tool
+// call is dispatched to a local ToolSpec, but it has to satisfy ToolCaller.
+type fakeToolCaller struct{}
+
+func (fakeToolCaller) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return nil, errors.New("fakeToolCaller: no MCP tools registered in this test")
+}
+
+func TestRunReturnsFinalAnswerWithNoToolCalls(t *testing.T) {
+	fp := &fakeProvider{responses: []provider.Chunk{
+		{ContentDelta: "hello there", Done: true},
+	}}
+	a := New(fp, fakeToolCaller{}, "test-model", nil)
+
+	got, err := a.Run(context.Background(), "hi", nil)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got != "hello there" {
+		t.Fatalf("got %q, want %q", got, "hello there")
+	}
+	if fp.calls != 1 {
+		t.Fatalf("expected exactly one model call, got %d", fp.calls)
+	}
+}
+
+func TestRunDispatchesLocalToolAndFeedsResultBack(t *testing.T) {
+	var gotArgs map[string]interface{}
+	a := New(&fakeProvider{responses: []provider.Chunk{
+		{Done: true, ToolCalls: []provider.ToolCall{
+			{ID: "call_1", Name: "echo", Arguments: map[string]interface{}{"msg": "ping"}},
+		}},
+		{ContentDelta: "done", Done: true},
+	}}, fakeToolCaller{}, "test-model", nil)
+
+	a.RegisterLocalTool(ToolSpec{
+		Name: "echo",
+		Impl: func(ctx context.Context, args map[string]interface{}) (CallResult, error) {
+			gotArgs = args
+			return CallResult{Content: "pong"}, nil
+		},
+	})
+
+	got, err := a.Run(context.Background(), "say ping", nil)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got != "done" {
+		t.Fatalf("got %q, want %q", got, "done")
+	}
+	if gotArgs["msg"] != "ping" {
+		t.Fatalf("tool did not receive the expected arguments: %v", gotArgs)
+	}
+
+	history := a.History()
+	var sawAssistantToolCall, sawToolResult bool
+	for _, m := range history {
+		if m.Role == "assistant" && len(m.ToolCalls) == 1 && m.ToolCalls[0].ID == "call_1" {
+			sawAssistantToolCall = true
+		}
+		if m.Role == "tool" && m.ToolCallID == "call_1" && m.ToolName == "echo" && m.Content == "pong" {
+			sawToolResult = true
+		}
+	}
+	if !sawAssistantToolCall {
+		t.Error("expected the assistant message carrying the tool call to be in history")
+	}
+	if !sawToolResult {
+		t.Error("expected the tool result, tagged with the originating call's ID and name, to be in history")
+	}
+}
+
+func TestRunRecordsFailedToolCallAsItsResult(t *testing.T) {
+	a := New(&fakeProvider{responses: []provider.Chunk{
+		{Done: true, ToolCalls: []provider.ToolCall{
+			{ID: "call_1", Name: "boom", Arguments: nil},
+		}},
+		{ContentDelta: "recovered", Done: true},
+	}}, fakeToolCaller{}, "test-model", nil)
+
+	a.RegisterLocalTool(ToolSpec{
+		Name: "boom",
+		Impl: func(ctx context.Context, args map[string]interface{}) (CallResult, error) {
+			return CallResult{}, errors.New("kaboom")
+		},
+	})
+
+	got, err := a.Run(context.Background(), "trigger the failure", nil)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got != "recovered" {
+		t.Fatalf("got %q, want %q", got, "recovered")
+	}
+}
+
+func TestRunHitsMaxIterations(t *testing.T) {
+	loop := provider.Chunk{Done: true, ToolCalls: []provider.ToolCall{{ID: "call_1", Name: "noop"}}}
+	fp := &fakeProvider{responses: []provider.Chunk{loop, loop, loop}}
+	a := New(fp, fakeToolCaller{}, "test-model", nil)
+	a.SetMaxIterations(3)
+	a.RegisterLocalTool(ToolSpec{
+		Name: "noop",
+		Impl: func(ctx context.Context, args map[string]interface{}) (CallResult, error) {
+			return CallResult{Content: "again"}, nil
+		},
+	})
+
+	_, err := a.Run(context.Background(), "loop forever", nil)
+	if err == nil {
+		t.Fatal("expected an error when the model never stops calling tools")
+	}
+}
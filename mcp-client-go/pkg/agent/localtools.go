@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxReadFileBytes caps how much of a local file read_file will return, so a
+// single tool call can't be used to exfiltrate or flood the conversation
+// with an arbitrarily large file.
+const maxReadFileBytes = 1 << 20 // 1 MiB
+
+// localToolsBaseDir resolves the directory dir_tree/read_file are confined
+// to, from LOCAL_TOOLS_BASE_DIR, defaulting to the working directory.
+// Symlinks are resolved up front so a symlinked base dir can't be used to
+// smuggle paths back out of it.
+func localToolsBaseDir() (string, error) {
+	base := os.Getenv("LOCAL_TOOLS_BASE_DIR")
+	if base == "" {
+		base = "."
+	}
+	abs, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("localtools: base dir %q: %w", base, err)
+	}
+	return resolved, nil
+}
+
+// confine resolves path against base and rejects it if it escapes base,
+// following symlinks first so a symlink inside base can't point outside it.
+// This is the one check standing between a prompt-injected page and a tool
+// call that reads arbitrary files (e.g. ~/.ssh/id_rsa, .env) into the
+// conversation sent to the configured LLM_PROVIDER.
+func confine(base, path string) (string, error) {
+	if path == "" {
+		path = "."
+	}
+	joined := path
+	if !filepath.IsAbs(path) {
+		joined = filepath.Join(base, path)
+	}
+	clean := filepath.Clean(joined)
+
+	resolved, err := filepath.EvalSymlinks(clean)
+	if err != nil {
+		resolved = clean
+	}
+
+	rel, err := filepath.Rel(base, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("localtools: path %q escapes the allowed directory", path)
+	}
+	return resolved, nil
+}
+
+// DirTreeTool lists the files under a directory so the model can see what's
+// available locally (e.g. previously saved extractions) before deciding
+// what to read or fetch next. Listing is confined to LOCAL_TOOLS_BASE_DIR
+// (default: the working directory) so a prompt-injected page can't steer it
+// outside.
+func DirTreeTool() ToolSpec {
+	return ToolSpec{
+		Name:        "dir_tree",
+		Description: "List files and directories under a given path, recursively.",
+		Parameters: mcp.ToolInputSchema{
+			Type:     "object",
+			Required: []string{"path"},
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory to list, relative or absolute.",
+				},
+			},
+		},
+		Impl: func(ctx context.Context, args map[string]interface{}) (CallResult, error) {
+			path, _ := args["path"].(string)
+
+			base, err := localToolsBaseDir()
+			if err != nil {
+				return CallResult{}, err
+			}
+			root, err := confine(base, path)
+			if err != nil {
+				return CallResult{}, err
+			}
+
+			var b strings.Builder
+			err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(root, p)
+				if err != nil {
+					rel = p
+				}
+				if info.IsDir() {
+					fmt.Fprintf(&b, "%s/\n", rel)
+				} else {
+					fmt.Fprintf(&b, "%s\n", rel)
+				}
+				return nil
+			})
+			if err != nil {
+				return CallResult{}, err
+			}
+			return CallResult{Content: b.String()}, nil
+		},
+	}
+}
+
+// ReadFileTool reads a local file's contents so the model can inspect
+// previously saved extractions or notes without re-fetching them. Reads are
+// confined to LOCAL_TOOLS_BASE_DIR (default: the working directory) and
+// capped at maxReadFileBytes, for the same reason as DirTreeTool.
+func ReadFileTool() ToolSpec {
+	return ToolSpec{
+		Name:        "read_file",
+		Description: "Read the full contents of a local text file.",
+		Parameters: mcp.ToolInputSchema{
+			Type:     "object",
+			Required: []string{"path"},
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path of the file to read.",
+				},
+			},
+		},
+		Impl: func(ctx context.Context, args map[string]interface{}) (CallResult, error) {
+			path, _ := args["path"].(string)
+			if path == "" {
+				return CallResult{}, fmt.Errorf("read_file: missing %q argument", "path")
+			}
+
+			base, err := localToolsBaseDir()
+			if err != nil {
+				return CallResult{}, err
+			}
+			resolved, err := confine(base, path)
+			if err != nil {
+				return CallResult{}, err
+			}
+
+			info, err := os.Stat(resolved)
+			if err != nil {
+				return CallResult{}, err
+			}
+			if info.Size() > maxReadFileBytes {
+				return CallResult{}, fmt.Errorf("read_file: %q is %d bytes, over the %d byte limit", path, info.Size(), maxReadFileBytes)
+			}
+
+			data, err := os.ReadFile(resolved)
+			if err != nil {
+				return CallResult{}, err
+			}
+			return CallResult{Content: string(data)}, nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfineAllowsPathsInsideBase(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "notes.txt"), []byte("hi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := confine(base, "notes.txt")
+	if err != nil {
+		t.Fatalf("confine rejected a path inside base: %v", err)
+	}
+	if resolved != filepath.Join(base, "notes.txt") {
+		t.Fatalf("got %q, want %q", resolved, filepath.Join(base, "notes.txt"))
+	}
+}
+
+func TestConfineRejectsDotDotTraversal(t *testing.T) {
+	base := t.TempDir()
+	if _, err := confine(base, "../../etc/passwd"); err == nil {
+		t.Fatal("expected confine to reject a ../ path escaping base")
+	}
+}
+
+func TestConfineRejectsAbsolutePathEscape(t *testing.T) {
+	base := t.TempDir()
+	if _, err := confine(base, "/etc/passwd"); err == nil {
+		t.Fatal("expected confine to reject an absolute path outside base")
+	}
+}
+
+func TestConfineRejectsSymlinkEscape(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("sensitive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(base, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported in this environment: %v", err)
+	}
+
+	if _, err := confine(base, filepath.Join("escape", "secret.txt")); err == nil {
+		t.Fatal("expected confine to reject a path through a symlink that escapes base")
+	}
+}
+
+func TestConfineDefaultsEmptyPathToBase(t *testing.T) {
+	base := t.TempDir()
+	resolved, err := confine(base, "")
+	if err != nil {
+		t.Fatalf("confine rejected an empty path: %v", err)
+	}
+	if resolved != base {
+		t.Fatalf("got %q, want base dir %q", resolved, base)
+	}
+}
@@ -0,0 +1,75 @@
+// Package mcptransport selects and constructs the mcp-go client for
+// whichever transport the user configured, so the rest of the program can
+// talk to a local stdio server or a remote SSE/HTTP one identically.
+package mcptransport
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/client"
+)
+
+// Config selects the MCP transport and, for remote transports, the server
+// to connect to.
+type Config struct {
+	Transport string
+	Endpoint  string
+}
+
+// ConfigFromEnv reads MCP_TRANSPORT (stdio | sse | http) and MCP_ENDPOINT,
+// defaulting to the stdio transport this client originally shipped with.
+func ConfigFromEnv() Config {
+	transport := os.Getenv("MCP_TRANSPORT")
+	if transport == "" {
+		transport = "stdio"
+	}
+	return Config{Transport: transport, Endpoint: os.Getenv("MCP_ENDPOINT")}
+}
+
+// NewClient constructs the mcp-go client selected by cfg.Transport. The
+// tool-listing and tool-call code paths are unchanged by the transport in
+// use - only construction differs.
+//
+// Unlike the stdio transport, mcp-go's SSE and streamable-HTTP clients don't
+// start their transport on construction - Initialize fails with "transport
+// not started yet" until Start is called. Start is documented as safe to
+// call unconditionally, so NewClient calls it here for every transport
+// rather than special-casing sse/http.
+func NewClient(ctx context.Context, cfg Config) (*client.Client, error) {
+	var c *client.Client
+	var err error
+
+	switch cfg.Transport {
+	case "", "stdio":
+		// Run the bundled TypeScript server as a local child process.
+		c, err = client.NewStdioMCPClient(
+			"bun",
+			[]string{}, // Empty ENV
+			"run",
+			"start:mcp",
+		)
+	case "sse":
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("mcptransport: MCP_ENDPOINT is required for the sse transport")
+		}
+		c, err = client.NewSSEMCPClient(cfg.Endpoint)
+	case "http":
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("mcptransport: MCP_ENDPOINT is required for the http transport")
+		}
+		c, err = client.NewStreamableHttpClient(cfg.Endpoint)
+	default:
+		return nil, fmt.Errorf("mcptransport: unknown MCP_TRANSPORT %q", cfg.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Start(ctx); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("mcptransport: failed to start %s transport: %w", cfg.Transport, err)
+	}
+	return c, nil
+}
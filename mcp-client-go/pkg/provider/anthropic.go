@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AnthropicProvider talks to the Anthropic Messages API.
+type AnthropicProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// NewAnthropicProvider builds an AnthropicProvider. baseURL defaults to the
+// public Anthropic API.
+func NewAnthropicProvider(baseURL, apiKey string) *AnthropicProvider {
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+	return &AnthropicProvider{baseURL: baseURL, apiKey: apiKey}
+}
+
+// ConvertTools converts MCP tools to Anthropic's tool format.
+func (p *AnthropicProvider) ConvertTools(tools []mcp.Tool) any {
+	out := make([]map[string]any, len(tools))
+	for i, tool := range tools {
+		out[i] = map[string]any{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"input_schema": map[string]any{
+				"type":       tool.InputSchema.Type,
+				"required":   tool.InputSchema.Required,
+				"properties": tool.InputSchema.Properties,
+			},
+		}
+	}
+	return out
+}
+
+// Chat streams a message using Anthropic's SSE event format
+// (content_block_delta / content_block_start / message_stop).
+func (p *AnthropicProvider) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	var system string
+	messages := make([]map[string]any, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		switch {
+		case m.Role == "system":
+			system = m.Content
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			blocks := make([]map[string]any, 0, len(m.ToolCalls)+1)
+			if m.Content != "" {
+				blocks = append(blocks, map[string]any{"type": "text", "text": m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				blocks = append(blocks, map[string]any{
+					"type":  "tool_use",
+					"id":    tc.ID,
+					"name":  tc.Name,
+					"input": tc.Arguments,
+				})
+			}
+			messages = append(messages, map[string]any{"role": "assistant", "content": blocks})
+		case m.Role == "tool":
+			messages = append(messages, map[string]any{
+				"role": "user",
+				"content": []map[string]any{{
+					"type":        "tool_result",
+					"tool_use_id": m.ToolCallID,
+					"content":     m.Content,
+				}},
+			})
+		default:
+			messages = append(messages, map[string]any{"role": m.Role, "content": m.Content})
+		}
+	}
+
+	maxTokens := 4096
+	if v, ok := req.Options["num_predict"].(int); ok && v > 0 {
+		maxTokens = v
+	}
+
+	body := map[string]any{
+		"model":      req.Model,
+		"messages":   messages,
+		"max_tokens": maxTokens,
+		"stream":     true,
+	}
+	if system != "" {
+		body["system"] = system
+	}
+	if req.Tools != nil {
+		body["tools"] = req.Tools
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("anthropic chat request failed: %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		var toolCalls []ToolCall
+		var currentToolJSON strings.Builder
+		var currentToolName, currentToolID string
+		inToolBlock := false
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+			var event struct {
+				Type  string `json:"type"`
+				Delta struct {
+					Type        string `json:"type"`
+					Text        string `json:"text"`
+					PartialJSON string `json:"partial_json"`
+				} `json:"delta"`
+				ContentBlock struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"content_block"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+
+			switch event.Type {
+			case "content_block_start":
+				if event.ContentBlock.Type == "tool_use" {
+					inToolBlock = true
+					currentToolID = event.ContentBlock.ID
+					currentToolName = event.ContentBlock.Name
+					currentToolJSON.Reset()
+				}
+			case "content_block_delta":
+				if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+					out <- Chunk{ContentDelta: event.Delta.Text}
+				}
+				if event.Delta.Type == "input_json_delta" && inToolBlock {
+					currentToolJSON.WriteString(event.Delta.PartialJSON)
+				}
+			case "content_block_stop":
+				if inToolBlock {
+					var args map[string]interface{}
+					_ = json.Unmarshal([]byte(currentToolJSON.String()), &args)
+					toolCalls = append(toolCalls, ToolCall{ID: currentToolID, Name: currentToolName, Arguments: args})
+					inToolBlock = false
+				}
+			case "message_stop":
+				out <- Chunk{Done: true, ToolCalls: toolCalls}
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			out <- Chunk{Err: fmt.Errorf("anthropic: reading chat stream: %w", err)}
+		}
+	}()
+
+	return out, nil
+}
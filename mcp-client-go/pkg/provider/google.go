@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// GoogleProvider talks to the Gemini generateContent streaming API.
+type GoogleProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// NewGoogleProvider builds a GoogleProvider. baseURL defaults to the public
+// Generative Language API.
+func NewGoogleProvider(baseURL, apiKey string) *GoogleProvider {
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	return &GoogleProvider{baseURL: baseURL, apiKey: apiKey}
+}
+
+// ConvertTools converts MCP tools to Gemini's functionDeclarations format.
+func (p *GoogleProvider) ConvertTools(tools []mcp.Tool) any {
+	declarations := make([]map[string]any, len(tools))
+	for i, tool := range tools {
+		declarations[i] = map[string]any{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"parameters": map[string]any{
+				"type":       "object",
+				"required":   tool.InputSchema.Required,
+				"properties": tool.InputSchema.Properties,
+			},
+		}
+	}
+	return []map[string]any{{"functionDeclarations": declarations}}
+}
+
+// Chat streams a generateContent call over SSE (`alt=sse`).
+func (p *GoogleProvider) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	var system string
+	contents := make([]map[string]any, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		switch {
+		case m.Role == "system":
+			system = m.Content
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			parts := make([]map[string]any, 0, len(m.ToolCalls)+1)
+			if m.Content != "" {
+				parts = append(parts, map[string]any{"text": m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				parts = append(parts, map[string]any{
+					"functionCall": map[string]any{"name": tc.Name, "args": tc.Arguments},
+				})
+			}
+			contents = append(contents, map[string]any{"role": "model", "parts": parts})
+		case m.Role == "tool":
+			contents = append(contents, map[string]any{
+				"role": "user",
+				"parts": []map[string]any{{
+					"functionResponse": map[string]any{
+						"name":     m.ToolName,
+						"response": map[string]any{"content": m.Content},
+					},
+				}},
+			})
+		default:
+			role := "user"
+			if m.Role == "assistant" {
+				role = "model"
+			}
+			contents = append(contents, map[string]any{
+				"role":  role,
+				"parts": []map[string]any{{"text": m.Content}},
+			})
+		}
+	}
+
+	body := map[string]any{"contents": contents}
+	if system != "" {
+		body["systemInstruction"] = map[string]any{"parts": []map[string]any{{"text": system}}}
+	}
+	if req.Tools != nil {
+		body["tools"] = req.Tools
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", p.baseURL, req.Model, p.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("gemini chat request failed: %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+			var event struct {
+				Candidates []struct {
+					Content struct {
+						Parts []struct {
+							Text         string `json:"text"`
+							FunctionCall *struct {
+								Name string                 `json:"name"`
+								Args map[string]interface{} `json:"args"`
+							} `json:"functionCall"`
+						} `json:"parts"`
+					} `json:"content"`
+					FinishReason string `json:"finishReason"`
+				} `json:"candidates"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+			if len(event.Candidates) == 0 {
+				continue
+			}
+			candidate := event.Candidates[0]
+
+			chunk := Chunk{Done: candidate.FinishReason != ""}
+			for _, part := range candidate.Content.Parts {
+				if part.Text != "" {
+					chunk.ContentDelta += part.Text
+				}
+				if part.FunctionCall != nil {
+					chunk.ToolCalls = append(chunk.ToolCalls, ToolCall{
+						Name:      part.FunctionCall.Name,
+						Arguments: part.FunctionCall.Args,
+					})
+				}
+			}
+			out <- chunk
+		}
+		if err := scanner.Err(); err != nil {
+			out <- Chunk{Err: fmt.Errorf("gemini: reading chat stream: %w", err)}
+		}
+	}()
+
+	return out, nil
+}
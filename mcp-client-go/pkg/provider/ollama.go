@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ollama/ollama/api"
+)
+
+// OllamaProvider talks to a local (or remote) Ollama server via its native
+// /api/chat endpoint.
+type OllamaProvider struct {
+	client *api.Client
+}
+
+// NewOllamaProvider builds an OllamaProvider. baseURL defaults to
+// OLLAMA_HOST, then http://localhost:11434, matching the client's original
+// standalone behavior.
+func NewOllamaProvider(baseURL string) (*OllamaProvider, error) {
+	if baseURL == "" {
+		baseURL = os.Getenv("OLLAMA_HOST")
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OllamaProvider{client: api.NewClient(parsed, http.DefaultClient)}, nil
+}
+
+// ConvertTools converts MCP tools to Ollama's function-calling format.
+func (p *OllamaProvider) ConvertTools(tools []mcp.Tool) any {
+	return convertToOllamaTools(tools)
+}
+
+// Chat streams a chat completion, merging tool-call deltas across chunks
+// before emitting them on the final, Done chunk.
+func (p *OllamaProvider) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	out := make(chan Chunk)
+
+	messages := make([]api.Message, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = api.Message{Role: m.Role, Content: m.Content}
+		if len(m.ToolCalls) > 0 {
+			messages[i].ToolCalls = convertToOllamaToolCalls(m.ToolCalls)
+		}
+	}
+
+	var ollamaTools []api.Tool
+	if req.Tools != nil {
+		ollamaTools, _ = req.Tools.([]api.Tool)
+	}
+
+	var TRUE = true
+	chatReq := &api.ChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Options:  req.Options,
+		Tools:    ollamaTools,
+		Stream:   &TRUE,
+	}
+
+	go func() {
+		defer close(out)
+
+		// Unlike OpenAI, Ollama doesn't stream tool calls as partial deltas
+		// keyed by index - each ChatResponse.Message.ToolCalls, when
+		// present, is already the complete set for that turn (in practice
+		// delivered on the final, Done chunk). So there's nothing to merge:
+		// just remember the most recent non-empty set.
+		var toolCalls []api.ToolCall
+		err := p.client.Chat(ctx, chatReq, func(resp api.ChatResponse) error {
+			if len(resp.Message.ToolCalls) > 0 {
+				toolCalls = resp.Message.ToolCalls
+			}
+
+			chunk := Chunk{
+				ContentDelta: resp.Message.Content,
+				Done:         resp.Done,
+			}
+			if resp.Done {
+				chunk.ToolCalls = convertOllamaToolCalls(toolCalls)
+			}
+			out <- chunk
+			return nil
+		})
+		if err != nil {
+			out <- Chunk{Err: fmt.Errorf("ollama: chat request failed: %w", err)}
+		}
+	}()
+
+	return out, nil
+}
+
+func convertOllamaToolCalls(calls []api.ToolCall) []ToolCall {
+	result := make([]ToolCall, len(calls))
+	for i, c := range calls {
+		result[i] = ToolCall{
+			Name:      c.Function.Name,
+			Arguments: map[string]interface{}(c.Function.Arguments),
+		}
+	}
+	return result
+}
+
+// convertToOllamaToolCalls converts the provider-agnostic tool calls on an
+// assistant message back into Ollama's wire format, so a second chat turn
+// tells Ollama what it called earlier instead of silently dropping it.
+func convertToOllamaToolCalls(calls []ToolCall) []api.ToolCall {
+	result := make([]api.ToolCall, len(calls))
+	for i, c := range calls {
+		result[i] = api.ToolCall{
+			Function: api.ToolCallFunction{
+				Name:      c.Name,
+				Arguments: api.ToolCallFunctionArguments(c.Arguments),
+			},
+		}
+	}
+	return result
+}
+
+// convertToOllamaTools converts MCP tools to Ollama format.
+func convertToOllamaTools(tools []mcp.Tool) []api.Tool {
+	ollamaTools := make([]api.Tool, len(tools))
+	for i, tool := range tools {
+		ollamaTools[i] = api.Tool{
+			Type: "function",
+			Function: api.ToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters: struct {
+					Type       string   `json:"type"`
+					Required   []string `json:"required"`
+					Properties map[string]struct {
+						Type        string   `json:"type"`
+						Description string   `json:"description"`
+						Enum        []string `json:"enum,omitempty"`
+					} `json:"properties"`
+				}{
+					Type:       tool.InputSchema.Type,
+					Required:   tool.InputSchema.Required,
+					Properties: convertOllamaProperties(tool.InputSchema.Properties),
+				},
+			},
+		}
+	}
+	return ollamaTools
+}
+
+// convertOllamaProperties converts MCP property schemas to Ollama's format.
+func convertOllamaProperties(props map[string]interface{}) map[string]struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Enum        []string `json:"enum,omitempty"`
+} {
+	result := make(map[string]struct {
+		Type        string   `json:"type"`
+		Description string   `json:"description"`
+		Enum        []string `json:"enum,omitempty"`
+	})
+
+	for name, prop := range props {
+		if propMap, ok := prop.(map[string]interface{}); ok {
+			entry := struct {
+				Type        string   `json:"type"`
+				Description string   `json:"description"`
+				Enum        []string `json:"enum,omitempty"`
+			}{
+				Type:        getString(propMap, "type"),
+				Description: getString(propMap, "description"),
+			}
+
+			if enumRaw, ok := propMap["enum"].([]interface{}); ok {
+				for _, e := range enumRaw {
+					if str, ok := e.(string); ok {
+						entry.Enum = append(entry.Enum, str)
+					}
+				}
+			}
+
+			result[name] = entry
+		}
+	}
+
+	return result
+}
+
+// getString safely reads a string value from a decoded JSON schema map.
+func getString(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+	return ""
+}
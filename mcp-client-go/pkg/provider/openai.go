@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OpenAIProvider talks to the OpenAI chat completions API.
+type OpenAIProvider struct {
+	openAIStyleProvider
+}
+
+// NewOpenAIProvider builds an OpenAIProvider. baseURL defaults to the public
+// OpenAI API.
+func NewOpenAIProvider(baseURL, apiKey string) *OpenAIProvider {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &OpenAIProvider{openAIStyleProvider{baseURL: baseURL, apiKey: apiKey}}
+}
+
+// OpenAICompatibleProvider talks to any server implementing the OpenAI chat
+// completions wire format (vLLM, LM Studio, OpenRouter, etc.) at a
+// user-supplied base URL.
+type OpenAICompatibleProvider struct {
+	openAIStyleProvider
+}
+
+// NewOpenAICompatibleProvider builds a provider against an arbitrary
+// OpenAI-compatible endpoint. baseURL is required via LLM_BASE_URL.
+func NewOpenAICompatibleProvider(baseURL, apiKey string) *OpenAICompatibleProvider {
+	return &OpenAICompatibleProvider{openAIStyleProvider{baseURL: baseURL, apiKey: apiKey}}
+}
+
+// openAIStyleProvider implements the chat-completions streaming protocol
+// shared by OpenAI and any OpenAI-compatible server.
+type openAIStyleProvider struct {
+	baseURL string
+	apiKey  string
+}
+
+// ConvertTools converts MCP tools to OpenAI's function-calling tool format.
+func (p *openAIStyleProvider) ConvertTools(tools []mcp.Tool) any {
+	out := make([]map[string]any, len(tools))
+	for i, tool := range tools {
+		out[i] = map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters": map[string]any{
+					"type":       tool.InputSchema.Type,
+					"required":   tool.InputSchema.Required,
+					"properties": tool.InputSchema.Properties,
+				},
+			},
+		}
+	}
+	return out
+}
+
+// openAIPassthroughOptions lists the Options keys OpenAI's API actually
+// recognizes at the top level of a chat completion request. Options is
+// shared across providers (it also carries Ollama-specific keys like
+// num_predict/repeat_last_n), so only these are forwarded.
+var openAIPassthroughOptions = map[string]bool{
+	"temperature":       true,
+	"top_p":             true,
+	"max_tokens":        true,
+	"presence_penalty":  true,
+	"frequency_penalty": true,
+}
+
+// Chat streams a chat completion using OpenAI's SSE format
+// (`data: {...}` lines, terminated by `data: [DONE]`).
+func (p *openAIStyleProvider) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	messages := make([]map[string]any, len(req.Messages))
+	for i, m := range req.Messages {
+		switch {
+		case m.Role == "assistant" && len(m.ToolCalls) > 0:
+			toolCalls := make([]map[string]any, len(m.ToolCalls))
+			for j, tc := range m.ToolCalls {
+				args, _ := json.Marshal(tc.Arguments)
+				toolCalls[j] = map[string]any{
+					"id":   tc.ID,
+					"type": "function",
+					"function": map[string]any{
+						"name":      tc.Name,
+						"arguments": string(args),
+					},
+				}
+			}
+			messages[i] = map[string]any{"role": "assistant", "content": m.Content, "tool_calls": toolCalls}
+		case m.Role == "tool":
+			messages[i] = map[string]any{"role": "tool", "tool_call_id": m.ToolCallID, "content": m.Content}
+		default:
+			messages[i] = map[string]any{"role": m.Role, "content": m.Content}
+		}
+	}
+
+	body := map[string]any{
+		"model":    req.Model,
+		"messages": messages,
+		"stream":   true,
+	}
+	if req.Tools != nil {
+		body["tools"] = req.Tools
+	}
+	for k, v := range req.Options {
+		if openAIPassthroughOptions[k] {
+			body[k] = v
+		}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", strings.NewReader(string(payload)))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("openai-style chat request failed: %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		type toolCallDelta struct {
+			ID       string `json:"id"`
+			Function struct {
+				Name      string `json:"name"`
+				Arguments string `json:"arguments"`
+			} `json:"function"`
+		}
+
+		accumArgs := map[int]*strings.Builder{}
+		accumNames := map[int]string{}
+		accumIDs := map[int]string{}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				out <- Chunk{Done: true, ToolCalls: flushOpenAIToolCalls(accumIDs, accumNames, accumArgs)}
+				return
+			}
+
+			var event struct {
+				Choices []struct {
+					Delta struct {
+						Content   string          `json:"content"`
+						ToolCalls []toolCallDelta `json:"tool_calls"`
+					} `json:"delta"`
+					FinishReason *string `json:"finish_reason"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+			if len(event.Choices) == 0 {
+				continue
+			}
+			choice := event.Choices[0]
+
+			for i, tc := range choice.Delta.ToolCalls {
+				if tc.ID != "" {
+					accumIDs[i] = tc.ID
+				}
+				if tc.Function.Name != "" {
+					accumNames[i] = tc.Function.Name
+				}
+				if accumArgs[i] == nil {
+					accumArgs[i] = &strings.Builder{}
+				}
+				accumArgs[i].WriteString(tc.Function.Arguments)
+			}
+
+			done := choice.FinishReason != nil
+			chunk := Chunk{ContentDelta: choice.Delta.Content, Done: done}
+			if done {
+				chunk.ToolCalls = flushOpenAIToolCalls(accumIDs, accumNames, accumArgs)
+			}
+			out <- chunk
+		}
+		if err := scanner.Err(); err != nil {
+			out <- Chunk{Err: fmt.Errorf("openai-style: reading chat stream: %w", err)}
+		}
+	}()
+
+	return out, nil
+}
+
+// flushOpenAIToolCalls turns the accumulated per-index tool-call deltas into
+// complete ToolCalls, parsing each one's JSON argument string.
+func flushOpenAIToolCalls(ids, names map[int]string, args map[int]*strings.Builder) []ToolCall {
+	if len(names) == 0 {
+		return nil
+	}
+	indices := make([]int, 0, len(names))
+	for i := range names {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	calls := make([]ToolCall, 0, len(indices))
+	for _, i := range indices {
+		var parsed map[string]interface{}
+		if b, ok := args[i]; ok {
+			_ = json.Unmarshal([]byte(b.String()), &parsed)
+		}
+		calls = append(calls, ToolCall{ID: ids[i], Name: names[i], Arguments: parsed})
+	}
+	return calls
+}
@@ -0,0 +1,107 @@
+// Package provider abstracts the chat + tool-calling loop over multiple LLM
+// backends so the MCP tool-calling client isn't tied to Ollama. Each backend
+// implements ChatProvider and owns its own wire format; callers only ever
+// deal in provider-agnostic Message/Chunk/ToolCall values.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Message is a single turn in the conversation, provider-agnostic. An
+// assistant turn that called tools carries them in ToolCalls alongside any
+// text it produced. A "tool" role message is that call's result; ToolCallID
+// and ToolName identify which call it answers, since each hosted API's wire
+// format needs one or the other (or both) to splice the result back into
+// the right place.
+type Message struct {
+	Role       string
+	Content    string
+	ToolCalls  []ToolCall
+	ToolCallID string
+	ToolName   string
+}
+
+// ToolCall is a model-issued request to invoke a tool by name with arguments.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments map[string]interface{}
+}
+
+// Chunk is one piece of a streamed chat response. ContentDelta holds
+// newly-generated text (empty once the model stops producing text);
+// ToolCalls holds any tool calls complete as of this chunk. Done is set on
+// the final chunk of the stream. Err is set instead of Done when the stream
+// ended abnormally (e.g. the connection dropped mid-read) so a caller can
+// tell a dropped stream apart from a normal completion rather than treating
+// whatever partial content arrived as the whole answer.
+type Chunk struct {
+	ContentDelta string
+	ToolCalls    []ToolCall
+	Done         bool
+	Err          error
+}
+
+// ChatRequest is a provider-agnostic chat request. Tools must already be in
+// the shape returned by the provider's own ConvertTools.
+type ChatRequest struct {
+	Model    string
+	Messages []Message
+	Tools    any
+	Options  map[string]interface{}
+}
+
+// ChatProvider is implemented by each LLM backend. Chat starts a streaming
+// request and returns a channel of Chunks; the channel is closed once the
+// final chunk (Done == true) has been sent or the request fails. ConvertTools
+// turns MCP tool descriptions into whatever shape the backend's API expects.
+type ChatProvider interface {
+	Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error)
+	ConvertTools(tools []mcp.Tool) any
+}
+
+// Config selects and configures a provider, normally sourced from env vars.
+type Config struct {
+	Provider string
+	Model    string
+	BaseURL  string
+	APIKey   string
+}
+
+// ConfigFromEnv reads LLM_PROVIDER, LLM_MODEL, LLM_BASE_URL and LLM_API_KEY,
+// defaulting to the Ollama provider this client originally shipped with.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Provider: os.Getenv("LLM_PROVIDER"),
+		Model:    os.Getenv("LLM_MODEL"),
+		BaseURL:  os.Getenv("LLM_BASE_URL"),
+		APIKey:   os.Getenv("LLM_API_KEY"),
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "ollama"
+	}
+	return cfg
+}
+
+// New constructs the ChatProvider selected by cfg.Provider.
+func New(cfg Config) (ChatProvider, error) {
+	switch cfg.Provider {
+	case "ollama":
+		return NewOllamaProvider(cfg.BaseURL)
+	case "openai":
+		return NewOpenAIProvider(cfg.BaseURL, cfg.APIKey), nil
+	case "anthropic":
+		return NewAnthropicProvider(cfg.BaseURL, cfg.APIKey), nil
+	case "google", "gemini":
+		return NewGoogleProvider(cfg.BaseURL, cfg.APIKey), nil
+	case "openai-compatible", "compatible":
+		return NewOpenAICompatibleProvider(cfg.BaseURL, cfg.APIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q", cfg.Provider)
+	}
+}
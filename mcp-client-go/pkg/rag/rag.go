@@ -0,0 +1,240 @@
+// Package rag chunks extracted page content, embeds the chunks via Ollama's
+// embeddings endpoint, and retrieves the top-k chunks most relevant to a
+// question instead of stuffing the whole page into the prompt.
+package rag
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultChunkWords and DefaultOverlapWords approximate ~500-token chunks
+// (at a rough 0.75 words/token) with enough overlap that an answer spanning
+// a chunk boundary still surfaces.
+const (
+	DefaultChunkWords   = 375
+	DefaultOverlapWords = 50
+	DefaultTopK         = 5
+)
+
+// EmbedFunc embeds a single piece of text into a vector.
+type EmbedFunc func(ctx context.Context, text string) ([]float64, error)
+
+// entry is everything stored for one embedded page.
+type entry struct {
+	Chunks  []string
+	Vectors [][]float64
+}
+
+// Collection is an in-memory, optionally disk-persisted store of embedded
+// page chunks, keyed by Key(url, content).
+type Collection struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]entry
+}
+
+// NewCollection builds a Collection, loading any previously persisted
+// entries from path if it exists. path may be empty, in which case the
+// collection is in-memory only.
+func NewCollection(path string) (*Collection, error) {
+	c := &Collection{path: path, entries: make(map[string]entry)}
+	if path == "" {
+		return c, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&c.entries); err != nil {
+		return nil, fmt.Errorf("rag: failed to load collection from %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save persists the collection to disk if it was built with a path.
+func (c *Collection) Save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.entries); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, buf.Bytes(), 0o644)
+}
+
+// Key derives a stable collection key from a URL and its content, so
+// repeat queries over an unchanged page skip re-embedding while an edited
+// page re-embeds under a new key.
+func Key(url, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return url + "#" + hex.EncodeToString(sum[:8])
+}
+
+// Ingest splits text into overlapping chunks and embeds each one, unless key
+// is already present in the collection.
+func (c *Collection) Ingest(ctx context.Context, key, text string, embed EmbedFunc) error {
+	c.mu.Lock()
+	_, exists := c.entries[key]
+	c.mu.Unlock()
+	if exists {
+		return nil
+	}
+
+	chunks := Split(text, DefaultChunkWords, DefaultOverlapWords)
+	vectors := make([][]float64, len(chunks))
+	for i, chunk := range chunks {
+		vector, err := embed(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("rag: failed to embed chunk %d: %w", i, err)
+		}
+		vectors[i] = vector
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry{Chunks: chunks, Vectors: vectors}
+	c.mu.Unlock()
+	return nil
+}
+
+// Search embeds query and returns the k chunks under key with the highest
+// cosine similarity to it.
+func (c *Collection) Search(ctx context.Context, key, query string, k int, embed EmbedFunc) ([]string, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok || len(e.Chunks) == 0 {
+		return nil, nil
+	}
+
+	queryVector, err := embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("rag: failed to embed query: %w", err)
+	}
+
+	type scored struct {
+		chunk string
+		score float64
+	}
+	results := make([]scored, len(e.Chunks))
+	for i, chunk := range e.Chunks {
+		results[i] = scored{chunk: chunk, score: cosineSimilarity(queryVector, e.Vectors[i])}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	if k > len(results) {
+		k = len(results)
+	}
+	top := make([]string, k)
+	for i := 0; i < k; i++ {
+		top[i] = results[i].chunk
+	}
+	return top, nil
+}
+
+// Split breaks text into overlapping chunks of roughly chunkWords words,
+// each overlapping the previous chunk by overlapWords words.
+func Split(text string, chunkWords, overlapWords int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if chunkWords <= overlapWords {
+		chunkWords = overlapWords + 1
+	}
+
+	var chunks []string
+	step := chunkWords - overlapWords
+	for start := 0; start < len(words); start += step {
+		end := start + chunkWords
+		if end > len(words) {
+			end = len(words)
+		}
+		chunks = append(chunks, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			break
+		}
+	}
+	return chunks
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// OllamaEmbedder builds an EmbedFunc that POSTs to Ollama's /api/embeddings
+// endpoint using the given base URL (defaults to http://localhost:11434)
+// and model (defaults to nomic-embed-text, overridable via EMBED_MODEL).
+func OllamaEmbedder(baseURL, model string) EmbedFunc {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+
+	return func(ctx context.Context, text string) ([]float64, error) {
+		body, err := json.Marshal(map[string]string{"model": model, "prompt": text})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/embeddings", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("rag: embeddings request failed: %s", resp.Status)
+		}
+
+		var decoded struct {
+			Embedding []float64 `json:"embedding"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			return nil, err
+		}
+		return decoded.Embedding, nil
+	}
+}
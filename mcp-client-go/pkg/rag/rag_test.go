@@ -0,0 +1,77 @@
+package rag
+
+import "testing"
+
+func TestSplitShortTextReturnsSingleChunk(t *testing.T) {
+	chunks := Split("the quick brown fox", 375, 50)
+	if len(chunks) != 1 || chunks[0] != "the quick brown fox" {
+		t.Fatalf("expected a single unchanged chunk, got %v", chunks)
+	}
+}
+
+func TestSplitEmptyTextReturnsNoChunks(t *testing.T) {
+	if chunks := Split("   ", 375, 50); chunks != nil {
+		t.Fatalf("expected nil for empty text, got %v", chunks)
+	}
+}
+
+func TestSplitOverlapsAcrossChunkBoundaries(t *testing.T) {
+	words := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		words = append(words, string(rune('a'+i)))
+	}
+	text := ""
+	for i, w := range words {
+		if i > 0 {
+			text += " "
+		}
+		text += w
+	}
+
+	chunks := Split(text, 4, 2)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple overlapping chunks, got %v", chunks)
+	}
+	if chunks[0] != "a b c d" {
+		t.Fatalf("unexpected first chunk: %q", chunks[0])
+	}
+	if chunks[1] != "c d e f" {
+		t.Fatalf("expected the second chunk to overlap the first by 2 words, got %q", chunks[1])
+	}
+}
+
+func TestSplitNeverLoopsForeverWhenOverlapCoversChunk(t *testing.T) {
+	// chunkWords <= overlapWords must be corrected internally, otherwise step
+	// would be <= 0 and the loop would never advance past start=0.
+	chunks := Split("a b c d e f", 2, 5)
+	if len(chunks) == 0 {
+		t.Fatal("expected at least one chunk")
+	}
+}
+
+func TestCosineSimilarityIdenticalVectors(t *testing.T) {
+	v := []float64{1, 2, 3}
+	if got := cosineSimilarity(v, v); got < 0.999999 || got > 1.000001 {
+		t.Fatalf("expected similarity ~1 for identical vectors, got %v", got)
+	}
+}
+
+func TestCosineSimilarityOrthogonalVectors(t *testing.T) {
+	a := []float64{1, 0}
+	b := []float64{0, 1}
+	if got := cosineSimilarity(a, b); got != 0 {
+		t.Fatalf("expected similarity 0 for orthogonal vectors, got %v", got)
+	}
+}
+
+func TestCosineSimilarityMismatchedLengthsReturnsZero(t *testing.T) {
+	if got := cosineSimilarity([]float64{1, 2}, []float64{1, 2, 3}); got != 0 {
+		t.Fatalf("expected 0 for mismatched-length vectors, got %v", got)
+	}
+}
+
+func TestCosineSimilarityZeroVectorReturnsZero(t *testing.T) {
+	if got := cosineSimilarity([]float64{0, 0}, []float64{1, 1}); got != 0 {
+		t.Fatalf("expected 0 when one vector has zero norm, got %v", got)
+	}
+}
@@ -0,0 +1,153 @@
+// Package repl provides a plain readline-based interactive mode: it keeps
+// the agent and its message history alive across turns instead of the
+// one-shot query main ran before, and understands a handful of slash
+// commands for steering the session.
+//
+// A bubbletea TUI with a dedicated tool-activity side panel would be a
+// richer front end for this same loop, but isn't wired up here; this
+// readline loop is the fallback the request calls for and is enough to
+// drive multi-turn, multi-URL conversations from a terminal.
+package repl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/agent"
+	"github.com/gzuuus/web-content-extractor/mcp-client-go/pkg/provider"
+)
+
+// REPL drives an interactive, multi-turn conversation with an Agent.
+type REPL struct {
+	agent *agent.Agent
+	in    *bufio.Scanner
+	out   io.Writer
+}
+
+// New builds a REPL reading from in and writing to out.
+func New(a *agent.Agent, in io.Reader, out io.Writer) *REPL {
+	r := &REPL{agent: a, in: bufio.NewScanner(in), out: out}
+	r.in.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	a.OnToolCall = func(call provider.ToolCall) {
+		fmt.Fprintf(out, "\n🛠️  %s %v\n", call.Name, call.Arguments)
+	}
+
+	return r
+}
+
+// IsTerminal reports whether f looks like an interactive terminal rather
+// than a pipe or redirected file, used to decide whether to enter the REPL
+// without an explicit -i flag.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Run loops reading a line of input at a time until EOF or /quit, dispatching
+// slash commands and otherwise feeding the line to the agent as a user
+// message, streaming its reply token-by-token.
+func (r *REPL) Run(ctx context.Context) error {
+	fmt.Fprintln(r.out, "🔎 Interactive mode. Type a question or a URL to discuss; /help lists commands.")
+
+	for {
+		fmt.Fprint(r.out, "\n> ")
+		if !r.in.Scan() {
+			return r.in.Err()
+		}
+
+		line := strings.TrimSpace(r.in.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			if quit := r.handleCommand(line); quit {
+				return nil
+			}
+			continue
+		}
+
+		printedHeader := false
+		onChunk := func(chunk provider.Chunk) {
+			if chunk.ContentDelta == "" {
+				return
+			}
+			if !printedHeader {
+				fmt.Fprint(r.out, "\n🦙 ")
+				printedHeader = true
+			}
+			fmt.Fprint(r.out, chunk.ContentDelta)
+		}
+
+		if _, err := r.agent.Run(ctx, line, onChunk); err != nil {
+			fmt.Fprintf(r.out, "\n😡 %v\n", err)
+			continue
+		}
+		if printedHeader {
+			fmt.Fprintln(r.out)
+		}
+	}
+}
+
+// handleCommand runs a /slash command and reports whether the REPL should
+// exit.
+func (r *REPL) handleCommand(line string) (quit bool) {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/quit", "/exit":
+		return true
+
+	case "/reset":
+		r.agent.Reset()
+		fmt.Fprintln(r.out, "🔄 Conversation history cleared.")
+
+	case "/model":
+		if len(fields) < 2 {
+			fmt.Fprintf(r.out, "current model: %s\n", r.agent.Model())
+			return false
+		}
+		r.agent.SetModel(fields[1])
+		fmt.Fprintf(r.out, "🦙 Switched model to %s\n", fields[1])
+
+	case "/tools":
+		for _, name := range r.agent.ToolNames() {
+			fmt.Fprintf(r.out, "- %s\n", name)
+		}
+
+	case "/save":
+		if len(fields) < 2 {
+			fmt.Fprintln(r.out, "usage: /save <file>")
+			return false
+		}
+		if err := r.save(fields[1]); err != nil {
+			fmt.Fprintf(r.out, "😡 Failed to save: %v\n", err)
+		} else {
+			fmt.Fprintf(r.out, "💾 Saved conversation to %s\n", fields[1])
+		}
+
+	case "/help":
+		fmt.Fprintln(r.out, "/reset          clear the conversation\n/model <name>   switch models\n/tools          list registered tools\n/save <file>    save the transcript\n/quit           exit")
+
+	default:
+		fmt.Fprintf(r.out, "unknown command %q; try /help\n", fields[0])
+	}
+	return false
+}
+
+// save writes the conversation history to path as plain text, one message
+// per line.
+func (r *REPL) save(path string) error {
+	var b strings.Builder
+	for _, m := range r.agent.History() {
+		fmt.Fprintf(&b, "[%s] %s\n", m.Role, m.Content)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}